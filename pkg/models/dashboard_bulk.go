@@ -0,0 +1,17 @@
+package models
+
+import "errors"
+
+// ErrDashboardAccessDenied is recorded against an id in a bulk dashboard
+// operation's Result when the caller lacks the permission level that id
+// requires; see filterAllowedIds in sqlstore.
+var ErrDashboardAccessDenied = errors.New("dashboard access denied")
+
+// BulkDashboardEvent is published once per batch by the bulk dashboard
+// operations (delete, move, set permissions), in place of one event per
+// dashboard.
+type BulkDashboardEvent struct {
+	Name  string
+	OrgId int64
+	Ids   []int64
+}
@@ -0,0 +1,14 @@
+package models
+
+// DeleteDashboardCommand deletes a dashboard or folder. By default the
+// target is soft-deleted into the trash bin; set Permanent to bypass the
+// trash bin and run the hard-delete cascade immediately, as provisioning
+// sync and PurgeDashboard both rely on. UserId is stored as deleted_by on
+// the soft-deleted row and on its dashboard_trash audit row.
+type DeleteDashboardCommand struct {
+	Id                     int64
+	OrgId                  int64
+	ForceDeleteFolderRules bool
+	Permanent              bool
+	UserId                 int64
+}
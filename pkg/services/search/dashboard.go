@@ -0,0 +1,91 @@
+package search
+
+import "github.com/grafana/grafana/pkg/models"
+
+// HitType distinguishes a folder from a plain dashboard in a search result.
+type HitType string
+
+const (
+	DashHitDB     HitType = "dash-db"
+	DashHitFolder HitType = "dash-folder"
+)
+
+// SortOption describes one of the selectable search result orderings.
+// MetaName labels the metric a non-alphabetical sort exposes on each Hit
+// (e.g. "viewed"); Filter lets the chosen sort contribute the searchstore
+// filter that joins in whatever table the metric is read from.
+type SortOption struct {
+	Name     string
+	Display  string
+	MetaName string
+	Filter   []interface{}
+}
+
+// FacetBucket is one value/count pair in a facet aggregation.
+type FacetBucket struct {
+	Value string `xorm:"value"`
+	Count int64  `xorm:"count"`
+}
+
+// Hit is a single dashboard or folder search result.
+type Hit struct {
+	ID          int64    `json:"id"`
+	UID         string   `json:"uid"`
+	Title       string   `json:"title"`
+	URI         string   `json:"uri"`
+	URL         string   `json:"url"`
+	Type        HitType  `json:"type"`
+	FolderID    int64    `json:"folderId,omitempty"`
+	FolderUID   string   `json:"folderUid,omitempty"`
+	FolderTitle string   `json:"folderTitle,omitempty"`
+	FolderURL   string   `json:"folderUrl,omitempty"`
+	Tags        []string `json:"tags"`
+
+	SortMeta     int64  `json:"sortMeta,omitempty"`
+	SortMetaName string `json:"sortMetaName,omitempty"`
+
+	Rank       float64 `json:"rank,omitempty"`
+	Highlights string  `json:"highlights,omitempty"`
+}
+
+// FindPersistedDashboardsQuery searches dashboards and folders a user can
+// see. SearchDashboards populates Result (and FacetResult, when Facets is
+// set); FindDashboards is the lower-level entry point the cursor and facet
+// helpers build on top of, and returns the raw DashboardSearchProjection
+// rows instead.
+type FindPersistedDashboardsQuery struct {
+	Title        string
+	OrgId        int64
+	SignedInUser *models.SignedInUser
+	Tags         []string
+	DashboardIds []int64
+	IsStarred    bool
+	Query        string
+	Type         string
+	FolderIds    []int64
+	Permission   models.PermissionType
+	Sort         SortOption
+
+	// IncludeTrashed includes soft-deleted dashboards and folders in the
+	// result set; when false, the default, trashed rows are excluded.
+	IncludeTrashed bool
+
+	Limit int
+	Page  int
+
+	// Cursor, when set, switches FindDashboards from page-offset to
+	// keyset pagination: Limit still bounds the page size, but Page is
+	// ignored. NextCursor/PrevCursor are populated on the way out, and
+	// either can be fed back in as Cursor to walk forward or backward.
+	Cursor     string
+	NextCursor string
+	PrevCursor string
+
+	// Facets lists the dimensions SearchDashboards should also aggregate
+	// counts for, alongside the normal search. FacetResult holds the
+	// aggregation, keyed by dimension.
+	Facets      []string
+	FacetResult map[string][]FacetBucket
+
+	Result []*Hit
+}
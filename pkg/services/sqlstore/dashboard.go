@@ -41,6 +41,8 @@ func (ss *SQLStore) addDashboardQueryAndCommandHandlers() {
 	bus.AddHandler("sql", ss.GetDashboardsByPluginId)
 	bus.AddHandler("sql", ss.GetDashboardSlugById)
 	bus.AddHandler("sql", ss.HasAdminPermissionInFolders)
+
+	ss.addDashboardBulkQueryAndCommandHandlers()
 }
 
 var generateNewUid func() string = util.GenerateShortUID
@@ -79,9 +81,24 @@ type DashboardSearchProjection struct {
 	FolderSlug  string
 	FolderTitle string
 	SortMeta    int64
+	Rank        float64 `xorm:"rank"`
+	Highlights  string  `xorm:"highlights"`
 }
 
-func (ss *SQLStore) FindDashboards(ctx context.Context, query *search.FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error) {
+// Facet dimension names, shared between query.Facets and the exclusion
+// argument to buildSearchFilters.
+const (
+	facetDimensionTags   = "tags"
+	facetDimensionType   = "type"
+	facetDimensionFolder = "folderId"
+)
+
+// buildSearchFilters assembles the filter set for a search query. Pass
+// excludeDimension when building a facet aggregation so that dimension's own
+// active filter doesn't constrain its own buckets - e.g. the tag-facet query
+// must drop the caller's TagsFilter or every bucket but the tag they already
+// selected discards to empty.
+func (ss *SQLStore) buildSearchFilters(query *search.FindPersistedDashboardsQuery, excludeDimension string) []interface{} {
 	filters := []interface{}{
 		permissions.DashboardPermissionFilter{
 			OrgRole:         query.SignedInUser.OrgRole,
@@ -108,7 +125,7 @@ func (ss *SQLStore) FindDashboards(ctx context.Context, query *search.FindPersis
 		filters = append(filters, searchstore.OrgFilter{OrgId: query.SignedInUser.OrgId})
 	}
 
-	if len(query.Tags) > 0 {
+	if len(query.Tags) > 0 && excludeDimension != facetDimensionTags {
 		filters = append(filters, searchstore.TagsFilter{Tags: query.Tags})
 	}
 
@@ -120,32 +137,63 @@ func (ss *SQLStore) FindDashboards(ctx context.Context, query *search.FindPersis
 		filters = append(filters, searchstore.StarredFilter{UserId: query.SignedInUser.UserId})
 	}
 
-	if len(query.Title) > 0 {
+	if len(query.Query) > 0 && ss.Cfg.IsFeatureToggleEnabled("dashboardFullTextSearch") {
+		filters = append(filters, searchstore.FullTextFilter{Dialect: dialect, Query: query.Query})
+	} else if len(query.Title) > 0 {
 		filters = append(filters, searchstore.TitleFilter{Dialect: dialect, Title: query.Title})
 	}
 
-	if len(query.Type) > 0 {
+	if len(query.Type) > 0 && excludeDimension != facetDimensionType {
 		filters = append(filters, searchstore.TypeFilter{Dialect: dialect, Type: query.Type})
 	}
 
-	if len(query.FolderIds) > 0 {
+	if len(query.FolderIds) > 0 && excludeDimension != facetDimensionFolder {
 		filters = append(filters, searchstore.FolderFilter{IDs: query.FolderIds})
 	}
 
+	if !query.IncludeTrashed {
+		filters = append(filters, searchstore.NotTrashedFilter{})
+	}
+
+	return filters
+}
+
+func (ss *SQLStore) FindDashboards(ctx context.Context, query *search.FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error) {
+	filters := ss.buildSearchFilters(query, "")
+
+	sortKeyExpr := "dashboard.title"
+	if query.Sort.MetaName != "" {
+		sortKeyExpr = "sort_meta"
+	}
+
 	var res []DashboardSearchProjection
-	sb := &searchstore.Builder{Dialect: dialect, Filters: filters}
+	sb := &searchstore.Builder{Dialect: dialect, Filters: filters, SortKeyExpr: sortKeyExpr}
 
 	limit := query.Limit
 	if limit < 1 {
 		limit = 1000
 	}
 
-	page := query.Page
-	if page < 1 {
-		page = 1
-	}
+	var sql string
+	var params []interface{}
 
-	sql, params := sb.ToSQL(limit, page)
+	direction := searchstore.DirectionNext
+	hasCursor := len(query.Cursor) > 0
+
+	if hasCursor {
+		cursor, err := searchstore.DecodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		direction = cursor.Direction
+		sql, params = sb.ToSQLCursor(limit, cursor)
+	} else {
+		page := query.Page
+		if page < 1 {
+			page = 1
+		}
+		sql, params = sb.ToSQL(limit, page)
+	}
 
 	err := ss.WithDbSession(ctx, func(dbSession *DBSession) error {
 		return dbSession.SQL(sql, params...).Find(&res)
@@ -155,9 +203,50 @@ func (ss *SQLStore) FindDashboards(ctx context.Context, query *search.FindPersis
 		return nil, err
 	}
 
+	query.NextCursor, query.PrevCursor = searchCursors(query, res, limit, direction, hasCursor)
+
 	return res, nil
 }
 
+// cursorSortKey returns the value a row's keyset cursor should be anchored
+// on: the named sort metric when one is in play (recently-viewed, alert
+// state, ...), or the title itself for the default alphabetical sort. Rows
+// only carry a meaningful SortMeta when query.Sort.MetaName is set, so
+// alphabetical order has to key off Title instead or every cursor would
+// compare a column that's zero on every row and never advance.
+func cursorSortKey(query *search.FindPersistedDashboardsQuery, item DashboardSearchProjection) string {
+	if query.Sort.MetaName != "" {
+		return strconv.FormatInt(item.SortMeta, 10)
+	}
+	return item.Title
+}
+
+// searchCursors derives the opaque NextCursor/PrevCursor tokens for the
+// current page from the sort key and id of its first and last rows, each
+// replayed through ToSQLCursor in the matching direction. direction and
+// hasCursor record how the current page itself was fetched, so a short page
+// (fewer rows than the limit) only suppresses the cursor on the edge that
+// was actually reached: a short forward page means there's nothing further
+// forward, but may still have something behind it, and vice versa for a
+// short backward page. The very first page (no incoming cursor) never gets
+// a PrevCursor, since there's nothing before it regardless of page length.
+func searchCursors(query *search.FindPersistedDashboardsQuery, res []DashboardSearchProjection, limit int, direction searchstore.Direction, hasCursor bool) (next, prev string) {
+	if len(res) == 0 {
+		return "", ""
+	}
+
+	short := len(res) < limit
+	first, last := res[0], res[len(res)-1]
+
+	if hasCursor && !(short && direction == searchstore.DirectionPrev) {
+		prev = searchstore.EncodeCursor(cursorSortKey(query, first), first.ID, searchstore.DirectionPrev)
+	}
+	if !(short && direction == searchstore.DirectionNext) {
+		next = searchstore.EncodeCursor(cursorSortKey(query, last), last.ID, searchstore.DirectionNext)
+	}
+	return next, prev
+}
+
 func (ss *SQLStore) SearchDashboards(ctx context.Context, query *search.FindPersistedDashboardsQuery) error {
 	res, err := ss.FindDashboards(ctx, query)
 	if err != nil {
@@ -166,9 +255,72 @@ func (ss *SQLStore) SearchDashboards(ctx context.Context, query *search.FindPers
 
 	makeQueryResult(query, res)
 
+	if len(query.Facets) > 0 {
+		facets, err := ss.computeFacets(ctx, query)
+		if err != nil {
+			return err
+		}
+		query.FacetResult = facets
+	}
+
 	return nil
 }
 
+// facetCardinalityCap bounds how many distinct buckets a single facet
+// dimension returns; anything beyond it is folded into an "other" bucket so
+// a long tail of tags or folders can't blow up the response.
+const facetCardinalityCap = 20
+
+// computeFacets issues one aggregation query per requested dimension,
+// reusing the same permission, org and tag filters as the main search so
+// counts never include dashboards the caller can't see - except for the
+// dimension being aggregated itself, which is dropped from its own query so
+// a filter chip the user already selected doesn't collapse its own facet to
+// a single bucket.
+func (ss *SQLStore) computeFacets(ctx context.Context, query *search.FindPersistedDashboardsQuery) (map[string][]search.FacetBucket, error) {
+	result := make(map[string][]search.FacetBucket, len(query.Facets))
+	for _, dimension := range query.Facets {
+		filters := ss.buildSearchFilters(query, dimension)
+		sb := &searchstore.Builder{Dialect: dialect, Filters: filters}
+
+		sql, params, err := sb.ToFacetSQL(dimension)
+		if err != nil {
+			return nil, err
+		}
+
+		var buckets []search.FacetBucket
+		err = ss.WithDbSession(ctx, func(dbSession *DBSession) error {
+			return dbSession.SQL(sql, params...).Find(&buckets)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result[dimension] = capFacetBuckets(buckets, facetCardinalityCap)
+	}
+
+	return result, nil
+}
+
+// capFacetBuckets keeps the top N buckets by count and folds the remainder
+// into a single "other" bucket so high-cardinality dimensions (e.g. tags on
+// a large instance) stay cheap to render.
+func capFacetBuckets(buckets []search.FacetBucket, limit int) []search.FacetBucket {
+	if len(buckets) <= limit {
+		return buckets
+	}
+
+	var otherCount int64
+	for _, b := range buckets[limit:] {
+		otherCount += b.Count
+	}
+
+	capped := make([]search.FacetBucket, 0, limit+1)
+	capped = append(capped, buckets[:limit]...)
+	capped = append(capped, search.FacetBucket{Value: "other", Count: otherCount})
+	return capped
+}
+
 func getHitType(item DashboardSearchProjection) search.HitType {
 	var hitType search.HitType
 	if item.IsFolder {
@@ -209,6 +361,11 @@ func makeQueryResult(query *search.FindPersistedDashboardsQuery, res []Dashboard
 				hit.SortMetaName = query.Sort.MetaName
 			}
 
+			if len(query.Query) > 0 {
+				hit.Rank = item.Rank
+				hit.Highlights = item.Highlights
+			}
+
 			query.Result = append(query.Result, hit)
 			hits[item.ID] = hit
 		}
@@ -225,7 +382,7 @@ func (ss *SQLStore) GetDashboardTags(ctx context.Context, query *models.GetDashb
 						term
 					FROM dashboard
 					INNER JOIN dashboard_tag on dashboard_tag.dashboard_id = dashboard.id
-					WHERE dashboard.org_id=?
+					WHERE dashboard.org_id=? AND dashboard.deleted_at IS NULL
 					GROUP BY term
 					ORDER BY term`
 
@@ -236,13 +393,25 @@ func (ss *SQLStore) GetDashboardTags(ctx context.Context, query *models.GetDashb
 	})
 }
 
+// DeleteDashboard soft-deletes a dashboard or folder: the row is marked with
+// deleted_at/deleted_by and its dependents (ACLs, alert rules, annotations)
+// are moved into shadow tables rather than removed, so RestoreDashboard can
+// bring them back until the
+// trash reaper eventually purges them. Pass cmd.Permanent to skip the trash
+// bin and hard-delete immediately, as provisioning sync already relies on.
 func (ss *SQLStore) DeleteDashboard(ctx context.Context, cmd *models.DeleteDashboardCommand) error {
 	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
-		return deleteDashboard(cmd, sess)
+		if cmd.Permanent {
+			return hardDeleteDashboard(cmd, sess)
+		}
+		return trashDashboard(cmd, sess, cmd.UserId)
 	})
 }
 
-func deleteDashboard(cmd *models.DeleteDashboardCommand, sess *DBSession) error {
+// hardDeleteDashboard runs the cascade that used to back DeleteDashboard
+// directly. It's now only reached via a permanent delete or PurgeDashboard
+// working a trashed row.
+func hardDeleteDashboard(cmd *models.DeleteDashboardCommand, sess *DBSession) error {
 	dashboard := models.Dashboard{Id: cmd.Id, OrgId: cmd.OrgId}
 	has, err := sess.Get(&dashboard)
 	if err != nil {
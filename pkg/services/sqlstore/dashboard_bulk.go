@@ -0,0 +1,399 @@
+package sqlstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/sqlstore/permissions"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+)
+
+// BulkResult captures the outcome of one id in a bulk operation. A missing
+// permission on one dashboard, or a failure to trash or re-permission it, is
+// recorded here without aborting the rest of the batch; an error in a
+// statement shared by the whole batch, including the alert-rule folder guard
+// of a permanent delete, fails the whole batch.
+type BulkResult struct {
+	Id    int64
+	Error string
+}
+
+// BulkDeleteDashboardsCommand deletes (or trashes, see Permanent) a set of
+// dashboards and folders belonging to a single org in one transaction. Force
+// mirrors DeleteDashboardCommand.ForceDeleteFolderRules: without it, a
+// permanent delete of a folder that still has alert rules refuses the whole
+// batch rather than silently deleting those rules too.
+type BulkDeleteDashboardsCommand struct {
+	OrgId     int64
+	Ids       []int64
+	Permanent bool
+	Force     bool
+	User      *models.SignedInUser
+
+	Result []BulkResult
+}
+
+// BulkMoveDashboardsCommand moves a set of dashboards into a new folder in
+// one transaction.
+type BulkMoveDashboardsCommand struct {
+	OrgId       int64
+	Ids         []int64
+	NewFolderId int64
+	User        *models.SignedInUser
+
+	Result []BulkResult
+}
+
+// BulkSetPermissionsCommand replaces the ACL on a set of dashboards in one
+// transaction.
+type BulkSetPermissionsCommand struct {
+	OrgId       int64
+	Ids         []int64
+	Permissions []models.DashboardACL
+	User        *models.SignedInUser
+
+	Result []BulkResult
+}
+
+func (ss *SQLStore) addDashboardBulkQueryAndCommandHandlers() {
+	bus.AddHandler("sql", ss.BulkDeleteDashboards)
+	bus.AddHandler("sql", ss.BulkMoveDashboards)
+	bus.AddHandler("sql", ss.BulkSetPermissions)
+}
+
+// BulkDeleteDashboards deletes every id that the user is allowed to delete,
+// inside a single transaction, and reports the rest as per-id failures
+// rather than aborting the whole batch. For a permanent delete, IN (...)
+// statements replace the per-row cascade hardDeleteDashboard runs, so the
+// cost stays roughly constant per batch instead of per row; a soft delete
+// trashes each id in turn.
+func (ss *SQLStore) BulkDeleteDashboards(ctx context.Context, cmd *BulkDeleteDashboardsCommand) error {
+	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
+		allowed, results := filterAllowedIds(sess, cmd.OrgId, cmd.Ids, cmd.User, models.PERMISSION_ADMIN)
+		if len(allowed) == 0 {
+			cmd.Result = results
+			return nil
+		}
+
+		var folderIds []int64
+		err := sess.Table("dashboard").In("id", allowed).Where("is_folder = ?", dialect.BooleanStr(true)).Cols("id").Find(&folderIds)
+		if err != nil {
+			return err
+		}
+
+		if !cmd.Permanent {
+			for _, id := range allowed {
+				if err := trashDashboard(&models.DeleteDashboardCommand{Id: id, OrgId: cmd.OrgId, ForceDeleteFolderRules: true}, sess, cmd.User.UserId); err != nil {
+					results = appendBulkError(results, id, err)
+					continue
+				}
+				results = append(results, BulkResult{Id: id})
+			}
+			cmd.Result = results
+			return emitBulkEvent("dashboards.bulk_deleted", cmd.OrgId, allowed)
+		}
+
+		if err := guardFoldersAgainstAlertRules(sess, folderIds, cmd.Force); err != nil {
+			return err
+		}
+
+		if err := bulkHardDelete(sess, cmd.OrgId, allowed); err != nil {
+			return err
+		}
+
+		for _, id := range allowed {
+			results = append(results, BulkResult{Id: id})
+		}
+		cmd.Result = results
+
+		return emitBulkEvent("dashboards.bulk_deleted", cmd.OrgId, allowed)
+	})
+}
+
+// BulkMoveDashboards reassigns folder_id for every id the user may edit,
+// inside a single transaction. Folders themselves are excluded from the
+// UPDATE - and reported as per-id failures rather than successes - since
+// this path doesn't support re-parenting a folder, only the dashboards in
+// the batch.
+func (ss *SQLStore) BulkMoveDashboards(ctx context.Context, cmd *BulkMoveDashboardsCommand) error {
+	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
+		allowed, results := filterAllowedIds(sess, cmd.OrgId, cmd.Ids, cmd.User, models.PERMISSION_EDIT)
+		if len(allowed) == 0 {
+			cmd.Result = results
+			return nil
+		}
+
+		var folderIds []int64
+		if err := sess.Table("dashboard").In("id", allowed).Where("is_folder = ?", dialect.BooleanStr(true)).Cols("id").Find(&folderIds); err != nil {
+			return err
+		}
+		isFolder := make(map[int64]bool, len(folderIds))
+		for _, id := range folderIds {
+			isFolder[id] = true
+		}
+
+		movable := make([]int64, 0, len(allowed))
+		for _, id := range allowed {
+			if isFolder[id] {
+				results = append(results, BulkResult{Id: id, Error: "folders cannot be moved"})
+				continue
+			}
+			movable = append(movable, id)
+		}
+
+		if len(movable) == 0 {
+			cmd.Result = results
+			return nil
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(movable)), ",")
+		sql := fmt.Sprintf("UPDATE dashboard SET folder_id = ? WHERE org_id = ? AND is_folder = %s AND id IN (%s)",
+			dialect.BooleanStr(false), placeholders)
+
+		params := []interface{}{cmd.NewFolderId, cmd.OrgId}
+		for _, id := range movable {
+			params = append(params, id)
+		}
+
+		if _, err := sess.Exec(sql, params...); err != nil {
+			return err
+		}
+
+		for _, id := range movable {
+			results = append(results, BulkResult{Id: id})
+		}
+		cmd.Result = results
+
+		return emitBulkEvent("dashboards.bulk_moved", cmd.OrgId, movable)
+	})
+}
+
+// BulkSetPermissions overwrites the ACL for every id the user may administer,
+// inside a single transaction.
+func (ss *SQLStore) BulkSetPermissions(ctx context.Context, cmd *BulkSetPermissionsCommand) error {
+	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
+		allowed, results := filterAllowedIds(sess, cmd.OrgId, cmd.Ids, cmd.User, models.PERMISSION_ADMIN)
+		if len(allowed) == 0 {
+			cmd.Result = results
+			return nil
+		}
+
+		for _, id := range allowed {
+			if _, err := sess.Exec("DELETE FROM dashboard_acl WHERE dashboard_id = ?", id); err != nil {
+				results = appendBulkError(results, id, err)
+				continue
+			}
+
+			// A failed insert aborts the rest of this id's permission list
+			// rather than skipping just that one row: the ACL was already
+			// cleared above, so leaving later permissions unapplied would
+			// report success while granting less access than the caller
+			// asked for. There's no per-id sub-transaction to roll back the
+			// permissions that did insert, so the id is left with whatever
+			// applied so far and reported as a failure either way.
+			failed := false
+			for _, p := range cmd.Permissions {
+				p.DashboardId = id
+				if _, err := sess.Insert(&p); err != nil {
+					results = appendBulkError(results, id, err)
+					failed = true
+					break
+				}
+			}
+			if failed {
+				continue
+			}
+
+			results = append(results, BulkResult{Id: id})
+		}
+		cmd.Result = results
+
+		return emitBulkEvent("dashboards.bulk_permissions_set", cmd.OrgId, allowed)
+	})
+}
+
+// filterAllowedIds pre-checks the access-control guardian for every id and
+// splits them into ids the caller may act on and results recording the ones
+// it may not, so a single missing permission can't abort the whole batch.
+func filterAllowedIds(sess *DBSession, orgId int64, ids []int64, user *models.SignedInUser, level models.PermissionType) ([]int64, []BulkResult) {
+	var allowed []int64
+	var results []BulkResult
+
+	for _, id := range ids {
+		filter := permissions.AccessControlDashboardPermissionFilter{User: user, PermissionLevel: level}
+		ok, err := hasDashboardPermission(sess, filter, orgId, id)
+		if err != nil {
+			results = append(results, BulkResult{Id: id, Error: err.Error()})
+			continue
+		}
+		if !ok {
+			results = append(results, BulkResult{Id: id, Error: models.ErrDashboardAccessDenied.Error()})
+			continue
+		}
+		allowed = append(allowed, id)
+	}
+
+	return allowed, results
+}
+
+func hasDashboardPermission(sess *DBSession, filter permissions.AccessControlDashboardPermissionFilter, orgId, dashboardId int64) (bool, error) {
+	sb := &searchstore.Builder{Dialect: dialect, Filters: []interface{}{
+		filter,
+		searchstore.OrgFilter{OrgId: orgId},
+		searchstore.DashboardFilter{IDs: []int64{dashboardId}},
+	}}
+
+	sql, params := sb.ToSQL(1, 1)
+
+	var res []DashboardSearchProjection
+	if err := sess.SQL(sql, params...).Find(&res); err != nil {
+		return false, err
+	}
+
+	return len(res) > 0, nil
+}
+
+// guardFoldersAgainstAlertRules refuses a permanent bulk delete if any of
+// the target folders still has alert rules, mirroring the single-folder
+// guard in hardDeleteDashboard. force bypasses the guard the
+// same way ForceDeleteFolderRules does for a single folder: the rules get
+// deleted along with everything else in bulkHardDelete instead of blocking
+// the batch.
+func guardFoldersAgainstAlertRules(sess *DBSession, folderIds []int64, force bool) error {
+	if len(folderIds) == 0 || force {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(folderIds)), ",")
+	sql := fmt.Sprintf(`SELECT ar.id FROM alert_rule ar
+		WHERE ar.namespace_uid IN (SELECT uid FROM dashboard WHERE id IN (%s))`, placeholders)
+
+	var existingRuleIDs []int64
+	if err := sess.SQL(sql, toInterfaceSlice(folderIds)...).Find(&existingRuleIDs); err != nil {
+		return err
+	}
+	if len(existingRuleIDs) > 0 {
+		return fmt.Errorf("folder cannot be deleted: %w", models.ErrFolderContainsAlertRules)
+	}
+
+	return nil
+}
+
+// bulkHardDelete runs the same per-table cascade hardDeleteDashboard uses,
+// but once per table across the whole id set instead of once per dashboard.
+// Child dashboards pulled in by deleting a folder are folded into that same
+// id set up front, so every dependent table gets cleaned for them too -
+// otherwise only the folder's own rows would be swept and its children's
+// tag/star/version/annotation/provisioning/acl/permission rows would be
+// orphaned once the child `dashboard` rows disappear underneath them.
+func bulkHardDelete(sess *DBSession, orgId int64, ids []int64) error {
+	childIds, err := childDashboardIds(sess, orgId, ids)
+	if err != nil {
+		return err
+	}
+	ids = dedupeIds(append(append([]int64{}, ids...), childIds...))
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	params := toInterfaceSlice(ids)
+
+	deletes := []string{
+		"DELETE FROM dashboard_tag WHERE dashboard_id IN (%s)",
+		"DELETE FROM star WHERE dashboard_id IN (%s)",
+		"DELETE FROM dashboard_version WHERE dashboard_id IN (%s)",
+		"DELETE FROM annotation WHERE dashboard_id IN (%s)",
+		"DELETE FROM dashboard_provisioning WHERE dashboard_id IN (%s)",
+		"DELETE FROM dashboard_acl WHERE dashboard_id IN (%s)",
+		"DELETE FROM playlist_item WHERE type = 'dashboard_by_id' AND value IN (%s)",
+		"DELETE FROM alert_rule_version WHERE rule_namespace_uid IN (SELECT uid FROM dashboard WHERE id IN (%s))",
+		"DELETE FROM alert_rule WHERE namespace_uid IN (SELECT uid FROM dashboard WHERE id IN (%s))",
+	}
+	for _, sql := range deletes {
+		if _, err := sess.Exec(fmt.Sprintf(sql, placeholders), params...); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range ids {
+		if err := deleteAlertDefinition(id, sess); err != nil {
+			return err
+		}
+
+		// Folders carry folders:id scopes, dashboards dashboards:id; ids are
+		// unique across both, so clearing both scopes for every id is safe.
+		dashboardScope := ac.Scope("dashboards", "id", fmt.Sprintf("%d", id))
+		folderScope := ac.Scope("folders", "id", fmt.Sprintf("%d", id))
+		if _, err := sess.Exec("DELETE FROM permission WHERE scope IN (?, ?)", dashboardScope, folderScope); err != nil {
+			return err
+		}
+	}
+
+	if _, err := sess.Exec(fmt.Sprintf("DELETE FROM dashboard WHERE id IN (%s)", placeholders), params...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// childDashboardIds returns the dashboards filed under any of folderIds, so
+// bulkHardDelete can fold them into the same cascade as their parent folder.
+func childDashboardIds(sess *DBSession, orgId int64, folderIds []int64) ([]int64, error) {
+	if len(folderIds) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(folderIds)), ",")
+	params := append([]interface{}{orgId}, toInterfaceSlice(folderIds)...)
+
+	var rows []struct{ Id int64 }
+	sql := fmt.Sprintf("SELECT id FROM dashboard WHERE org_id = ? AND folder_id IN (%s)", placeholders)
+	if err := sess.SQL(sql, params...).Find(&rows); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.Id)
+	}
+	return ids, nil
+}
+
+func appendBulkError(results []BulkResult, id int64, err error) []BulkResult {
+	return append(results, BulkResult{Id: id, Error: err.Error()})
+}
+
+// dedupeIds drops repeats so a batch that explicitly names both a folder and
+// one of its own children doesn't process that child's cascade twice.
+func dedupeIds(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+func toInterfaceSlice(ids []int64) []interface{} {
+	out := make([]interface{}, len(ids))
+	for i, id := range ids {
+		out[i] = id
+	}
+	return out
+}
+
+// emitBulkEvent publishes a single bus event summarizing a bulk operation,
+// instead of one event per dashboard.
+func emitBulkEvent(name string, orgId int64, ids []int64) error {
+	return bus.Publish(&models.BulkDashboardEvent{
+		Name:  name,
+		OrgId: orgId,
+		Ids:   ids,
+	})
+}
@@ -0,0 +1,81 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+)
+
+func TestCursorSortKey(t *testing.T) {
+	t.Run("uses the title for the default alphabetical sort", func(t *testing.T) {
+		query := &search.FindPersistedDashboardsQuery{}
+		item := DashboardSearchProjection{ID: 1, Title: "Zebra dashboard", SortMeta: 0}
+
+		require.Equal(t, "Zebra dashboard", cursorSortKey(query, item))
+	})
+
+	t.Run("uses SortMeta when a named sort metric is active", func(t *testing.T) {
+		query := &search.FindPersistedDashboardsQuery{Sort: search.SortOption{MetaName: "viewed"}}
+		item := DashboardSearchProjection{ID: 1, Title: "Zebra dashboard", SortMeta: 42}
+
+		require.Equal(t, "42", cursorSortKey(query, item))
+	})
+}
+
+func TestSearchCursors(t *testing.T) {
+	query := &search.FindPersistedDashboardsQuery{}
+
+	t.Run("empty page has no cursors", func(t *testing.T) {
+		next, prev := searchCursors(query, nil, 10, searchstore.DirectionNext, false)
+		require.Empty(t, next)
+		require.Empty(t, prev)
+	})
+
+	t.Run("first page (no incoming cursor) has no prev even when short", func(t *testing.T) {
+		res := []DashboardSearchProjection{{ID: 1, Title: "A"}, {ID: 2, Title: "B"}}
+		next, prev := searchCursors(query, res, 10, searchstore.DirectionNext, false)
+		require.Empty(t, next)
+		require.Empty(t, prev)
+	})
+
+	t.Run("full first page has a next cursor but still no prev", func(t *testing.T) {
+		res := []DashboardSearchProjection{{ID: 1, Title: "A"}, {ID: 2, Title: "B"}}
+		next, prev := searchCursors(query, res, 2, searchstore.DirectionNext, false)
+		require.NotEmpty(t, next)
+		require.Empty(t, prev)
+	})
+
+	t.Run("a forward page that came from a cursor gets both a next and a prev", func(t *testing.T) {
+		res := []DashboardSearchProjection{{ID: 3, Title: "C"}, {ID: 4, Title: "D"}}
+		next, prev := searchCursors(query, res, 2, searchstore.DirectionNext, true)
+		require.NotEmpty(t, next)
+		require.NotEmpty(t, prev)
+	})
+
+	t.Run("a short forward page has no next, since it's the last page", func(t *testing.T) {
+		res := []DashboardSearchProjection{{ID: 3, Title: "C"}}
+		next, prev := searchCursors(query, res, 2, searchstore.DirectionNext, true)
+		require.Empty(t, next)
+		require.NotEmpty(t, prev)
+	})
+
+	t.Run("a short backward page has no prev, since it's the first page", func(t *testing.T) {
+		res := []DashboardSearchProjection{{ID: 1, Title: "A"}}
+		next, prev := searchCursors(query, res, 2, searchstore.DirectionPrev, true)
+		require.NotEmpty(t, next)
+		require.Empty(t, prev)
+	})
+
+	t.Run("consecutive full pages advance past the previous page's last row, not repeat it", func(t *testing.T) {
+		pageOne := []DashboardSearchProjection{{ID: 1, Title: "A"}, {ID: 2, Title: "B"}}
+		nextOne, _ := searchCursors(query, pageOne, 2, searchstore.DirectionNext, false)
+
+		pageTwo := []DashboardSearchProjection{{ID: 3, Title: "C"}, {ID: 4, Title: "D"}}
+		nextTwo, _ := searchCursors(query, pageTwo, 2, searchstore.DirectionNext, true)
+
+		require.NotEqual(t, nextOne, nextTwo)
+	})
+}
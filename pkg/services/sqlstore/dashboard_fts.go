@@ -0,0 +1,71 @@
+package sqlstore
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// ReindexDashboardFTS rebuilds the full-text index from scratch. It's meant
+// to be invoked once after the dashboard_fts migration runs against an
+// existing instance, and is safe to re-run any time the index is suspected
+// to have drifted from the dashboard/dashboard_tag tables.
+func (ss *SQLStore) ReindexDashboardFTS(ctx context.Context) error {
+	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
+		switch dialect.DriverName() {
+		case migrator.SQLite:
+			// dashboard_fts is a contentless (content='') fts5 table, so the
+			// 'rebuild' special command doesn't apply - it's only meaningful
+			// for tables backed by an external content table to resync from.
+			// Rebuild it by hand instead: clear the index and re-derive every
+			// row from dashboard/dashboard_tag, the same way the ai/au
+			// triggers do incrementally.
+			if _, err := sess.Exec("DELETE FROM dashboard_fts"); err != nil {
+				return err
+			}
+			if _, err := sess.Exec(`
+				INSERT INTO dashboard_fts(dashboard_id, title, folder_title, tags, description, panel_titles)
+				SELECT
+					dashboard.id,
+					dashboard.title,
+					COALESCE(folder.title, ''),
+					COALESCE((SELECT group_concat(term, ' ') FROM dashboard_tag WHERE dashboard_tag.dashboard_id = dashboard.id), ''),
+					dashboard.description,
+					COALESCE((SELECT group_concat(json_extract(value, '$.title'), ' ') FROM json_each(dashboard.data, '$.panels')), '')
+				FROM dashboard
+				LEFT JOIN dashboard AS folder ON folder.id = dashboard.folder_id
+			`); err != nil {
+				return err
+			}
+		case migrator.Postgres:
+			if _, err := sess.Exec(`
+				UPDATE dashboard SET search_vector =
+					setweight(to_tsvector('simple', coalesce(dashboard.title, '')), 'A') ||
+					setweight(to_tsvector('simple', coalesce(folder.title, '')), 'B') ||
+					setweight(to_tsvector('simple', coalesce(tagged.tags, '')), 'B') ||
+					setweight(to_tsvector('simple', coalesce(dashboard.description, '')), 'C') ||
+					setweight(to_tsvector('simple', coalesce(panels.titles, '')), 'C')
+				FROM dashboard AS src
+				LEFT JOIN dashboard AS folder ON folder.id = src.folder_id
+				LEFT JOIN (
+					SELECT dashboard_id, string_agg(term, ' ') AS tags
+					FROM dashboard_tag
+					GROUP BY dashboard_id
+				) AS tagged ON tagged.dashboard_id = src.id
+				LEFT JOIN (
+					SELECT id, string_agg(panel->>'title', ' ') AS titles
+					FROM dashboard, jsonb_array_elements(coalesce((data::jsonb)->'panels', '[]'::jsonb)) AS panel
+					GROUP BY id
+				) AS panels ON panels.id = src.id
+				WHERE dashboard.id = src.id
+			`); err != nil {
+				return err
+			}
+		case migrator.MySQL:
+			// MySQL FULLTEXT indexes are maintained transparently by InnoDB;
+			// nothing to rebuild explicitly.
+		}
+
+		return nil
+	})
+}
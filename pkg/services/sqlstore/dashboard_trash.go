@@ -0,0 +1,305 @@
+package sqlstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+var trashLogger = log.New("dashboard.trash")
+
+// defaultTrashRetention is used by RunTrashReaper when the operator has not
+// configured a retention window of their own.
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// RestoreDashboardCommand restores a dashboard or folder that was previously
+// soft-deleted via DeleteDashboard, undoing the detach into the shadow tables.
+type RestoreDashboardCommand struct {
+	OrgId int64
+	Id    int64
+}
+
+// PurgeDashboardCommand permanently removes a trashed dashboard or folder.
+// The target must already be soft-deleted; use DeleteDashboard first.
+type PurgeDashboardCommand struct {
+	OrgId int64
+	Id    int64
+}
+
+// ListTrashedDashboardsQuery lists the dashboards and folders in an org that
+// have been soft-deleted but not yet purged.
+type ListTrashedDashboardsQuery struct {
+	OrgId int64
+	Limit int
+
+	Result []*models.Dashboard
+}
+
+// trashRow mirrors the dashboard_trash audit row written alongside a
+// soft-delete: the dashboard's tags and version at that moment, and who
+// deleted it and when. It holds no ACL state; the ACL rows themselves are
+// moved to dashboard_acl_trash. RestoreDashboard does not read it back, it
+// only removes it.
+type trashRow struct {
+	DashboardId int64
+	OrgId       int64
+	Version     int64
+	Tags        string
+	DeletedAt   int64
+	DeletedBy   int64
+}
+
+// ListTrashed returns dashboards and folders that have been soft-deleted but
+// not yet purged, most recently deleted first.
+func (ss *SQLStore) ListTrashed(ctx context.Context, query *ListTrashedDashboardsQuery) error {
+	return ss.WithDbSession(ctx, func(dbSession *DBSession) error {
+		limit := query.Limit
+		if limit < 1 {
+			limit = 1000
+		}
+
+		var dashboards []*models.Dashboard
+		sess := dbSession.Where("org_id = ? AND deleted_at IS NOT NULL", query.OrgId).
+			Desc("deleted_at").Limit(limit)
+		if err := sess.Find(&dashboards); err != nil {
+			return err
+		}
+
+		query.Result = dashboards
+		return nil
+	})
+}
+
+// RestoreDashboard restores a soft-deleted dashboard or folder, re-attaching
+// the ACL, alert rule and annotation rows that were detached into shadow
+// tables on delete. Restoring a folder restores its trashed children in the
+// same transaction, so a subtree always comes back atomically.
+func (ss *SQLStore) RestoreDashboard(ctx context.Context, cmd *RestoreDashboardCommand) error {
+	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
+		dashboard := models.Dashboard{Id: cmd.Id, OrgId: cmd.OrgId}
+		has, err := sess.Where("deleted_at IS NOT NULL").Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !has {
+			return models.ErrDashboardNotFound
+		}
+
+		ids := []int64{dashboard.Id}
+		if dashboard.IsFolder {
+			childIds, err := trashedChildIds(sess, dashboard.OrgId, dashboard.Id)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, childIds...)
+		}
+
+		for _, id := range ids {
+			if err := restoreShadowRows(sess, id); err != nil {
+				return err
+			}
+			if _, err := sess.Exec("UPDATE dashboard SET deleted_at = NULL, deleted_by = NULL WHERE id = ?", id); err != nil {
+				return err
+			}
+			if _, err := sess.Exec("DELETE FROM dashboard_trash WHERE dashboard_id = ?", id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// PurgeDashboard permanently removes a soft-deleted dashboard or folder:
+// it drops the rows its soft-delete moved into the shadow tables, then
+// invokes the same cascade the old hard-delete used to run directly.
+func (ss *SQLStore) PurgeDashboard(ctx context.Context, cmd *PurgeDashboardCommand) error {
+	return ss.WithTransactionalDbSession(ctx, func(sess *DBSession) error {
+		dashboard := models.Dashboard{Id: cmd.Id, OrgId: cmd.OrgId}
+		has, err := sess.Where("deleted_at IS NOT NULL").Get(&dashboard)
+		if err != nil {
+			return err
+		} else if !has {
+			return models.ErrDashboardNotFound
+		}
+
+		// folder_id = ? picks up a folder's children; a dashboard has none.
+		shadowDeletes := []string{
+			"DELETE FROM dashboard_acl_trash WHERE dashboard_id IN (SELECT id FROM dashboard WHERE id = ? OR folder_id = ?)",
+			"DELETE FROM alert_rule_trash WHERE namespace_uid IN (SELECT uid FROM dashboard WHERE id = ? OR folder_id = ?)",
+			"DELETE FROM annotation_trash WHERE dashboard_id IN (SELECT id FROM dashboard WHERE id = ? OR folder_id = ?)",
+		}
+		for _, sql := range shadowDeletes {
+			if _, err := sess.Exec(sql, dashboard.Id, dashboard.Id); err != nil {
+				return err
+			}
+		}
+
+		return hardDeleteDashboard(&models.DeleteDashboardCommand{
+			Id:                     dashboard.Id,
+			OrgId:                  dashboard.OrgId,
+			ForceDeleteFolderRules: true,
+		}, sess)
+	})
+}
+
+// RunTrashReaper polls the trash bin on an interval and purges anything older
+// than retention. It blocks until ctx is cancelled, so callers should run it
+// in its own goroutine; retention <= 0 falls back to defaultTrashRetention.
+func (ss *SQLStore) RunTrashReaper(ctx context.Context, retention time.Duration) error {
+	if retention <= 0 {
+		retention = defaultTrashRetention
+	}
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := ss.reapExpiredTrash(ctx, retention); err != nil {
+				trashLogger.Error("failed to reap expired trash", "error", err)
+			}
+		}
+	}
+}
+
+func (ss *SQLStore) reapExpiredTrash(ctx context.Context, retention time.Duration) error {
+	cutoff := time.Now().Add(-retention).Unix()
+
+	var expired []struct {
+		Id    int64
+		OrgId int64
+	}
+	err := ss.WithDbSession(ctx, func(dbSession *DBSession) error {
+		return dbSession.SQL("SELECT id, org_id FROM dashboard WHERE deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Find(&expired)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, d := range expired {
+		if err := ss.PurgeDashboard(ctx, &PurgeDashboardCommand{Id: d.Id, OrgId: d.OrgId}); err != nil {
+			trashLogger.Error("failed to purge expired dashboard", "id", d.Id, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func trashedChildIds(sess *DBSession, orgId, folderId int64) ([]int64, error) {
+	var rows []struct{ Id int64 }
+	err := sess.SQL("SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ? AND deleted_at IS NOT NULL", orgId, folderId).Find(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.Id)
+	}
+	return ids, nil
+}
+
+// restoreShadowRows moves the ACL, alert rule and annotation rows detached by
+// trashDashboard back onto their live tables for a single dashboard id.
+func restoreShadowRows(sess *DBSession, dashboardId int64) error {
+	restores := []string{
+		"INSERT INTO dashboard_acl SELECT * FROM dashboard_acl_trash WHERE dashboard_id = ?",
+		"DELETE FROM dashboard_acl_trash WHERE dashboard_id = ?",
+		"INSERT INTO alert_rule SELECT * FROM alert_rule_trash WHERE namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
+		"DELETE FROM alert_rule_trash WHERE namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
+		"INSERT INTO annotation SELECT * FROM annotation_trash WHERE dashboard_id = ?",
+		"DELETE FROM annotation_trash WHERE dashboard_id = ?",
+	}
+	for _, sql := range restores {
+		if _, err := sess.Exec(sql, dashboardId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trashDashboard detaches a dashboard's dependents into shadow tables and
+// marks the row deleted instead of removing it outright. Folder deletion
+// recursively trashes children so a RestoreDashboard call can bring the
+// whole subtree back atomically.
+func trashDashboard(cmd *models.DeleteDashboardCommand, sess *DBSession, deletedBy int64) error {
+	dashboard := models.Dashboard{Id: cmd.Id, OrgId: cmd.OrgId}
+	has, err := sess.Get(&dashboard)
+	if err != nil {
+		return err
+	} else if !has {
+		return models.ErrDashboardNotFound
+	}
+
+	ids := []int64{dashboard.Id}
+	if dashboard.IsFolder {
+		var rows []struct{ Id int64 }
+		err := sess.SQL("SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?", dashboard.OrgId, dashboard.Id).Find(&rows)
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			ids = append(ids, r.Id)
+		}
+	}
+
+	for _, id := range ids {
+		if err := writeTrashRow(sess, dashboard.OrgId, id, deletedBy); err != nil {
+			return err
+		}
+		if err := detachDependents(sess, id); err != nil {
+			return err
+		}
+		if _, err := sess.Exec("UPDATE dashboard SET deleted_at = ?, deleted_by = ? WHERE id = ?", time.Now().Unix(), deletedBy, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeTrashRow(sess *DBSession, orgId, dashboardId, deletedBy int64) error {
+	var tags []string
+	if err := sess.SQL("SELECT term FROM dashboard_tag WHERE dashboard_id = ?", dashboardId).Find(&tags); err != nil {
+		return err
+	}
+
+	var version int64
+	if _, err := sess.SQL("SELECT version FROM dashboard WHERE id = ?", dashboardId).Get(&version); err != nil {
+		return err
+	}
+
+	row := trashRow{
+		DashboardId: dashboardId,
+		OrgId:       orgId,
+		Version:     version,
+		Tags:        strings.Join(tags, ","),
+		DeletedAt:   time.Now().Unix(),
+		DeletedBy:   deletedBy,
+	}
+	_, err := sess.Table("dashboard_trash").Insert(&row)
+	return err
+}
+
+func detachDependents(sess *DBSession, dashboardId int64) error {
+	detaches := []string{
+		"INSERT INTO dashboard_acl_trash SELECT * FROM dashboard_acl WHERE dashboard_id = ?",
+		"DELETE FROM dashboard_acl WHERE dashboard_id = ?",
+		"INSERT INTO alert_rule_trash SELECT * FROM alert_rule WHERE namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
+		"DELETE FROM alert_rule WHERE namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
+		"INSERT INTO annotation_trash SELECT * FROM annotation WHERE dashboard_id = ?",
+		"DELETE FROM annotation WHERE dashboard_id = ?",
+	}
+	for _, sql := range detaches {
+		if _, err := sess.Exec(sql, dashboardId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
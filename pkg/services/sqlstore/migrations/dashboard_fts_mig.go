@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// addDashboardFTSMigrations provisions the native full-text search backend
+// FindDashboards falls back to when the dashboardFullTextSearch toggle is on
+// and a free-text query is given: an FTS5 virtual table on SQLite, a
+// tsvector column + GIN index on Postgres, and a FULLTEXT index on MySQL.
+// ReindexDashboardFTS does the initial backfill once these land; it's also
+// safe to re-run any time the index is suspected to have drifted.
+func addDashboardFTSMigrations(mg *migrator.Migrator) {
+	switch mg.Dialect.DriverName() {
+	case migrator.SQLite:
+		mg.AddMigration("create dashboard_fts virtual table", migrator.NewRawSQLMigration(`
+			CREATE VIRTUAL TABLE dashboard_fts USING fts5(
+				dashboard_id UNINDEXED,
+				title,
+				folder_title,
+				tags,
+				description,
+				panel_titles,
+				content='',
+				tokenize='porter unicode61'
+			)
+		`))
+		// The ai/au triggers populate all five indexed columns, not just
+		// title/description: folder_title and tags are read back out via a
+		// correlated subquery (dashboard_tag has no foreign key to drive a
+		// join here), and panel_titles via json_each over the dashboard's own
+		// JSON. au deletes and fully re-inserts the row rather than updating
+		// it in place - contentless fts5 tables don't support UPDATE - so it
+		// must recompute every column on every save, or folder/tags/panel
+		// data that a previous reindex filled in would get silently wiped
+		// back out on the dashboard's next unrelated edit.
+		mg.AddMigration("add dashboard insert trigger for dashboard_fts", migrator.NewRawSQLMigration(`
+			CREATE TRIGGER dashboard_fts_ai AFTER INSERT ON dashboard BEGIN
+				INSERT INTO dashboard_fts(dashboard_id, title, folder_title, tags, description, panel_titles)
+				VALUES (
+					new.id,
+					new.title,
+					COALESCE((SELECT title FROM dashboard WHERE id = new.folder_id), ''),
+					COALESCE((SELECT group_concat(term, ' ') FROM dashboard_tag WHERE dashboard_id = new.id), ''),
+					new.description,
+					COALESCE((SELECT group_concat(json_extract(value, '$.title'), ' ') FROM json_each(new.data, '$.panels')), '')
+				);
+			END
+		`))
+		mg.AddMigration("add dashboard update trigger for dashboard_fts", migrator.NewRawSQLMigration(`
+			CREATE TRIGGER dashboard_fts_au AFTER UPDATE ON dashboard BEGIN
+				DELETE FROM dashboard_fts WHERE dashboard_id = old.id;
+				INSERT INTO dashboard_fts(dashboard_id, title, folder_title, tags, description, panel_titles)
+				VALUES (
+					new.id,
+					new.title,
+					COALESCE((SELECT title FROM dashboard WHERE id = new.folder_id), ''),
+					COALESCE((SELECT group_concat(term, ' ') FROM dashboard_tag WHERE dashboard_id = new.id), ''),
+					new.description,
+					COALESCE((SELECT group_concat(json_extract(value, '$.title'), ' ') FROM json_each(new.data, '$.panels')), '')
+				);
+			END
+		`))
+		mg.AddMigration("add dashboard delete trigger for dashboard_fts", migrator.NewRawSQLMigration(`
+			CREATE TRIGGER dashboard_fts_ad AFTER DELETE ON dashboard BEGIN
+				DELETE FROM dashboard_fts WHERE dashboard_id = old.id;
+			END
+		`))
+		// dashboard_tag has no trigger of its own before this, so tagging or
+		// untagging a dashboard without otherwise editing it never touched
+		// dashboard_fts.tags. These keep it in sync from the dashboard_tag
+		// side instead of requiring a full dashboard save.
+		mg.AddMigration("add dashboard_tag insert trigger for dashboard_fts", migrator.NewRawSQLMigration(`
+			CREATE TRIGGER dashboard_tag_fts_ai AFTER INSERT ON dashboard_tag BEGIN
+				UPDATE dashboard_fts SET tags = COALESCE((SELECT group_concat(term, ' ') FROM dashboard_tag WHERE dashboard_id = new.dashboard_id), '')
+				WHERE dashboard_id = new.dashboard_id;
+			END
+		`))
+		mg.AddMigration("add dashboard_tag delete trigger for dashboard_fts", migrator.NewRawSQLMigration(`
+			CREATE TRIGGER dashboard_tag_fts_ad AFTER DELETE ON dashboard_tag BEGIN
+				UPDATE dashboard_fts SET tags = COALESCE((SELECT group_concat(term, ' ') FROM dashboard_tag WHERE dashboard_id = old.dashboard_id), '')
+				WHERE dashboard_id = old.dashboard_id;
+			END
+		`))
+	case migrator.Postgres:
+		mg.AddMigration("add search_vector column to dashboard", migrator.NewRawSQLMigration(
+			"ALTER TABLE dashboard ADD COLUMN search_vector tsvector",
+		))
+		mg.AddMigration("add GIN index dashboard.search_vector", migrator.NewRawSQLMigration(
+			"CREATE INDEX IF NOT EXISTS idx_dashboard_search_vector ON dashboard USING gin(search_vector)",
+		))
+	case migrator.MySQL:
+		mg.AddMigration("add FULLTEXT index on dashboard title/description", migrator.NewRawSQLMigration(
+			"ALTER TABLE dashboard ADD FULLTEXT INDEX idx_dashboard_fulltext (title, description)",
+		))
+	}
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// addDashboardTrashMigrations wires up the trash bin introduced alongside
+// DeleteDashboard/RestoreDashboard/PurgeDashboard: a deleted_at/deleted_by
+// marker on dashboard itself, an audit row per soft-delete, and shadow
+// tables that hold the ACL/alert rule/annotation rows detached while a
+// dashboard is trashed. Existing rows backfill deleted_at/deleted_by as
+// NULL, i.e. "not trashed", which is the correct historical state.
+func addDashboardTrashMigrations(mg *migrator.Migrator) {
+	mg.AddMigration("add deleted_at column to dashboard", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "dashboard"},
+		&migrator.Column{Name: "deleted_at", Type: migrator.DB_BigInt, Nullable: true},
+	))
+
+	mg.AddMigration("add deleted_by column to dashboard", migrator.NewAddColumnMigration(
+		migrator.Table{Name: "dashboard"},
+		&migrator.Column{Name: "deleted_by", Type: migrator.DB_BigInt, Nullable: true},
+	))
+
+	mg.AddMigration("add index dashboard.deleted_at", migrator.NewAddIndexMigration(
+		migrator.Table{Name: "dashboard"},
+		&migrator.Index{Cols: []string{"org_id", "deleted_at"}},
+	))
+
+	mg.AddMigration("create dashboard_trash table", migrator.NewAddTableMigration(migrator.Table{
+		Name: "dashboard_trash",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "dashboard_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "tags", Type: migrator.DB_Text, Nullable: true},
+			{Name: "deleted_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "deleted_by", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"dashboard_id"}},
+		},
+	}))
+
+	// dashboard_acl_trash, alert_rule_trash and annotation_trash mirror the
+	// source table they shadow column-for-column, since
+	// detachDependents/restoreShadowRows move rows between them with a bare
+	// INSERT ... SELECT * / DELETE pair.
+	mg.AddMigration("create dashboard_acl_trash table", migrator.NewRawSQLMigration(
+		"CREATE TABLE dashboard_acl_trash AS SELECT * FROM dashboard_acl WHERE 1 = 0",
+	))
+	mg.AddMigration("add index dashboard_acl_trash.dashboard_id", migrator.NewAddIndexMigration(
+		migrator.Table{Name: "dashboard_acl_trash"},
+		&migrator.Index{Cols: []string{"dashboard_id"}},
+	))
+
+	mg.AddMigration("create alert_rule_trash table", migrator.NewRawSQLMigration(
+		"CREATE TABLE alert_rule_trash AS SELECT * FROM alert_rule WHERE 1 = 0",
+	))
+	mg.AddMigration("add index alert_rule_trash.namespace_uid", migrator.NewAddIndexMigration(
+		migrator.Table{Name: "alert_rule_trash"},
+		&migrator.Index{Cols: []string{"namespace_uid"}},
+	))
+
+	mg.AddMigration("create annotation_trash table", migrator.NewRawSQLMigration(
+		"CREATE TABLE annotation_trash AS SELECT * FROM annotation WHERE 1 = 0",
+	))
+	mg.AddMigration("add index annotation_trash.dashboard_id", migrator.NewAddIndexMigration(
+		migrator.Table{Name: "annotation_trash"},
+		&migrator.Index{Cols: []string{"dashboard_id"}},
+	))
+}
@@ -0,0 +1,12 @@
+package migrations
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddMigrations registers every migration the SQLStore applies on startup,
+// grouped by the feature that introduced them.
+func AddMigrations(mg *migrator.Migrator) {
+	addDashboardTrashMigrations(mg)
+	addDashboardFTSMigrations(mg)
+}
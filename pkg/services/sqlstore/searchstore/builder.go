@@ -0,0 +1,161 @@
+package searchstore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// Builder assembles the SELECT FindDashboards, and its facet/cursor
+// variants, run from a flat list of filters. Each filter contributes
+// whichever of FilterWhere/FilterLeftJoin/FilterOrderBy/FilterGroupBy/
+// FilterSelect it implements; Builder itself stays agnostic of what any
+// individual filter means.
+type Builder struct {
+	Dialect migrator.Dialect
+	Filters []interface{}
+
+	// SortKeyExpr is the SQL expression keyset cursors compare against -
+	// dashboard.title for the default alphabetical sort, or the active
+	// sort metric's column otherwise. Defaults to dashboard.title.
+	SortKeyExpr string
+}
+
+const baseSelect = `SELECT
+		dashboard.id AS id,
+		dashboard.uid AS uid,
+		dashboard.title AS title,
+		dashboard.slug AS slug,
+		dashboard.is_folder AS is_folder,
+		dashboard.folder_id AS folder_id,
+		folder.uid AS folder_uid,
+		folder.slug AS folder_slug,
+		folder.title AS folder_title,
+		filter_tag.term AS term`
+
+const baseFrom = `FROM dashboard
+	LEFT OUTER JOIN dashboard AS folder ON folder.id = dashboard.folder_id`
+
+func (b *Builder) sortKeyExpr() string {
+	if b.SortKeyExpr != "" {
+		return b.SortKeyExpr
+	}
+	return "dashboard.title"
+}
+
+// sortKeyColumn is the output column name sortKeyExpr is aliased to in
+// baseSelect/selectParts, for queries that need to re-sort on the already
+// projected result instead of re-evaluating the expression.
+func (b *Builder) sortKeyColumn() string {
+	if b.sortKeyExpr() == "dashboard.title" {
+		return "title"
+	}
+	return "sort_meta"
+}
+
+// selectParts builds the SELECT list, including the rank/highlights
+// expressions a FullTextFilter contributes, along with the parameters those
+// expressions bind - which appear before any WHERE parameter in the final
+// query.
+func (b *Builder) selectParts() (string, []interface{}) {
+	rank, highlights, params := "0", "''", []interface{}(nil)
+	for _, f := range b.Filters {
+		if sf, ok := f.(FilterSelect); ok {
+			rank, highlights, params = sf.Select()
+		}
+	}
+
+	sql := fmt.Sprintf("%s,\n\t\t%s AS sort_meta,\n\t\t%s AS rank,\n\t\t%s AS highlights", baseSelect, "0", rank, highlights)
+	return sql, params
+}
+
+func (b *Builder) joinParts() string {
+	var sb strings.Builder
+	sb.WriteString(baseFrom)
+	for _, f := range b.Filters {
+		if lj, ok := f.(FilterLeftJoin); ok {
+			sb.WriteString("\n\tLEFT OUTER JOIN " + lj.LeftJoin())
+		}
+	}
+	return sb.String()
+}
+
+func (b *Builder) whereParts() (string, []interface{}) {
+	var clauses []string
+	var params []interface{}
+	for _, f := range b.Filters {
+		wf, ok := f.(FilterWhere)
+		if !ok {
+			continue
+		}
+		clause, p := wf.Where()
+		if clause == "" {
+			continue
+		}
+		clauses = append(clauses, clause)
+		params = append(params, p...)
+	}
+	if len(clauses) == 0 {
+		return "", params
+	}
+	return "WHERE " + strings.Join(clauses, "\n\tAND "), params
+}
+
+func (b *Builder) groupByParts() string {
+	var cols []string
+	for _, f := range b.Filters {
+		if gb, ok := f.(FilterGroupBy); ok {
+			cols = append(cols, gb.GroupBy())
+		}
+	}
+	if len(cols) == 0 {
+		return ""
+	}
+	return "GROUP BY " + strings.Join(cols, ", ")
+}
+
+func (b *Builder) orderByExpr() string {
+	for _, f := range b.Filters {
+		if ob, ok := f.(FilterOrderBy); ok {
+			return ob.OrderBy()
+		}
+	}
+	return b.sortKeyExpr() + " ASC"
+}
+
+func filterEmpty(parts []string) []string {
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ToSQL builds a page-offset query: the limit/page'th slice of results in
+// the builder's sort order.
+func (b *Builder) ToSQL(limit, page int) (string, []interface{}) {
+	selectSQL, selectParams := b.selectParts()
+	whereSQL, whereParams := b.whereParts()
+
+	params := append(append([]interface{}{}, selectParams...), whereParams...)
+
+	offset := 0
+	if page > 1 {
+		offset = (page - 1) * limit
+	}
+
+	sql := strings.Join(filterEmpty([]string{
+		selectSQL,
+		b.joinParts(),
+		whereSQL,
+		b.groupByParts(),
+		"ORDER BY " + b.orderByExpr(),
+		"LIMIT ? OFFSET ?",
+	}), "\n\t")
+
+	params = append(params, limit, offset)
+	return sql, params
+}
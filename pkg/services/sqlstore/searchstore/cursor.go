@@ -0,0 +1,99 @@
+package searchstore
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Direction is which way a keyset cursor walks relative to the builder's
+// sort order: DirectionNext fetches the rows after the cursor's position,
+// DirectionPrev the rows before it.
+type Direction string
+
+const (
+	DirectionNext Direction = "next"
+	DirectionPrev Direction = "prev"
+)
+
+// Cursor is a decoded keyset pagination token: the sort key and id of the
+// row to walk forward or backward from.
+type Cursor struct {
+	Key       string
+	ID        int64
+	Direction Direction
+}
+
+// EncodeCursor packs a keyset position into the opaque token
+// FindPersistedDashboardsQuery.Cursor/NextCursor/PrevCursor carry.
+func EncodeCursor(key string, id int64, direction Direction) string {
+	raw := fmt.Sprintf("%s\x00%d\x00%s", key, id, direction)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverses EncodeCursor.
+func DecodeCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	parts := strings.Split(string(raw), "\x00")
+	if len(parts) != 3 {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	direction := Direction(parts[2])
+	if direction != DirectionNext && direction != DirectionPrev {
+		return Cursor{}, fmt.Errorf("invalid cursor direction %q", parts[2])
+	}
+
+	return Cursor{Key: parts[0], ID: id, Direction: direction}, nil
+}
+
+// ToSQLCursor builds a keyset-paginated query: limit rows strictly after
+// (DirectionNext) or before (DirectionPrev) the cursor's position in sort
+// order. A prev query runs the comparison and ORDER BY in reverse, so it
+// walks backward from the cursor using the same index a forward query
+// would, then the outer wrapper flips the page back to the builder's normal
+// ascending order before it's returned to the caller.
+func (b *Builder) ToSQLCursor(limit int, cursor Cursor) (string, []interface{}) {
+	selectSQL, selectParams := b.selectParts()
+	whereSQL, whereParams := b.whereParts()
+
+	cmp, order := ">", "ASC"
+	if cursor.Direction == DirectionPrev {
+		cmp, order = "<", "DESC"
+	}
+
+	keysetClause := fmt.Sprintf("(%s, dashboard.id) %s (?, ?)", b.sortKeyExpr(), cmp)
+	if whereSQL == "" {
+		whereSQL = "WHERE " + keysetClause
+	} else {
+		whereSQL += "\n\tAND " + keysetClause
+	}
+
+	params := append(append([]interface{}{}, selectParams...), whereParams...)
+	params = append(params, cursor.Key, cursor.ID)
+
+	inner := strings.Join(filterEmpty([]string{
+		selectSQL,
+		b.joinParts(),
+		whereSQL,
+		b.groupByParts(),
+		fmt.Sprintf("ORDER BY %s %s, dashboard.id %s", b.sortKeyExpr(), order, order),
+		"LIMIT ?",
+	}), "\n\t")
+	params = append(params, limit)
+
+	if cursor.Direction == DirectionPrev {
+		return fmt.Sprintf("SELECT * FROM (\n\t%s\n\t) AS page\n\tORDER BY %s ASC, id ASC", inner, b.sortKeyColumn()), params
+	}
+	return inner, params
+}
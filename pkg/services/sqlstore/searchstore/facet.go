@@ -0,0 +1,38 @@
+package searchstore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToFacetSQL builds the aggregation query computeFacets runs once per
+// requested facet dimension: one row per distinct value with its count,
+// reusing every filter the main search applies - except, by convention, the
+// dimension's own filter, which the caller drops before constructing the
+// Builder (see buildSearchFilters' excludeDimension parameter).
+func (b *Builder) ToFacetSQL(dimension string) (string, []interface{}, error) {
+	var valueExpr, joinExpr string
+	switch dimension {
+	case "tags":
+		valueExpr = "filter_tag.term"
+		joinExpr = "\n\tLEFT OUTER JOIN dashboard_tag AS filter_tag ON filter_tag.dashboard_id = dashboard.id"
+	case "type":
+		valueExpr = fmt.Sprintf("CASE WHEN dashboard.is_folder = %s THEN 'dash-folder' ELSE 'dash-db' END", b.Dialect.BooleanStr(true))
+	case "folderId":
+		valueExpr = "CAST(dashboard.folder_id AS VARCHAR)"
+	default:
+		return "", nil, fmt.Errorf("unsupported facet dimension %q", dimension)
+	}
+
+	whereSQL, whereParams := b.whereParts()
+
+	sql := strings.Join(filterEmpty([]string{
+		fmt.Sprintf("SELECT %s AS value, COUNT(DISTINCT dashboard.id) AS count", valueExpr),
+		b.joinParts() + joinExpr,
+		whereSQL,
+		fmt.Sprintf("GROUP BY %s", valueExpr),
+		"ORDER BY count DESC",
+	}), "\n\t")
+
+	return sql, whereParams, nil
+}
@@ -0,0 +1,35 @@
+package searchstore
+
+// FilterWhere contributes a single SQL condition (and its bound parameters)
+// to a Builder query's WHERE clause. Builder ANDs every filter's condition
+// together.
+type FilterWhere interface {
+	Where() (string, []interface{})
+}
+
+// FilterLeftJoin contributes a LEFT JOIN clause a filter's Where condition
+// depends on.
+type FilterLeftJoin interface {
+	LeftJoin() string
+}
+
+// FilterOrderBy overrides the query's default ORDER BY. Only one active
+// filter is expected to implement it at a time; if more than one does, the
+// first one in Builder.Filters wins.
+type FilterOrderBy interface {
+	OrderBy() string
+}
+
+// FilterGroupBy adds a column to the query's GROUP BY, for filters that only
+// make sense alongside an aggregate, such as TagsFilter's join against
+// dashboard_tag.
+type FilterGroupBy interface {
+	GroupBy() string
+}
+
+// FilterSelect overrides the rank and highlight snippet expressions Builder
+// otherwise defaults to "0" and "''". Only FullTextFilter implements it
+// today.
+type FilterSelect interface {
+	Select() (rankExpr, highlightsExpr string, params []interface{})
+}
@@ -0,0 +1,147 @@
+package searchstore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// OrgFilter restricts results to a single org.
+type OrgFilter struct {
+	OrgId int64
+}
+
+func (f OrgFilter) Where() (string, []interface{}) {
+	return "dashboard.org_id = ?", []interface{}{f.OrgId}
+}
+
+// TagsFilter restricts results to dashboards carrying any of the given tags.
+type TagsFilter struct {
+	Tags []string
+}
+
+func (f TagsFilter) LeftJoin() string {
+	return "dashboard_tag AS filter_tag ON filter_tag.dashboard_id = dashboard.id"
+}
+
+func (f TagsFilter) Where() (string, []interface{}) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(f.Tags)), ",")
+	params := make([]interface{}, len(f.Tags))
+	for i, t := range f.Tags {
+		params[i] = t
+	}
+	return fmt.Sprintf("filter_tag.term IN (%s)", placeholders), params
+}
+
+func (f TagsFilter) GroupBy() string {
+	return "dashboard.id"
+}
+
+// DashboardFilter restricts results to a specific set of dashboard ids.
+type DashboardFilter struct {
+	IDs []int64
+}
+
+func (f DashboardFilter) Where() (string, []interface{}) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(f.IDs)), ",")
+	params := make([]interface{}, len(f.IDs))
+	for i, id := range f.IDs {
+		params[i] = id
+	}
+	return fmt.Sprintf("dashboard.id IN (%s)", placeholders), params
+}
+
+// FolderFilter restricts results to dashboards filed under a specific set of
+// folder ids.
+type FolderFilter struct {
+	IDs []int64
+}
+
+func (f FolderFilter) Where() (string, []interface{}) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(f.IDs)), ",")
+	params := make([]interface{}, len(f.IDs))
+	for i, id := range f.IDs {
+		params[i] = id
+	}
+	return fmt.Sprintf("dashboard.folder_id IN (%s)", placeholders), params
+}
+
+// StarredFilter restricts results to dashboards a user has starred.
+type StarredFilter struct {
+	UserId int64
+}
+
+func (f StarredFilter) LeftJoin() string {
+	return "star ON star.dashboard_id = dashboard.id"
+}
+
+func (f StarredFilter) Where() (string, []interface{}) {
+	return "star.user_id = ?", []interface{}{f.UserId}
+}
+
+// TitleFilter restricts results to dashboards whose title contains a
+// substring, case-insensitively.
+type TitleFilter struct {
+	Dialect migrator.Dialect
+	Title   string
+}
+
+func (f TitleFilter) Where() (string, []interface{}) {
+	return "LOWER(dashboard.title) LIKE LOWER(?)", []interface{}{"%" + f.Title + "%"}
+}
+
+// TypeFilter restricts results to either dashboards or folders.
+type TypeFilter struct {
+	Dialect migrator.Dialect
+	Type    string
+}
+
+func (f TypeFilter) Where() (string, []interface{}) {
+	if f.Type == "dash-folder" {
+		return "dashboard.is_folder = " + f.Dialect.BooleanStr(true), nil
+	}
+	return "dashboard.is_folder = " + f.Dialect.BooleanStr(false), nil
+}
+
+// NotTrashedFilter excludes soft-deleted dashboards and folders. Every
+// search applies it unless the caller explicitly asked to include trashed
+// rows via FindPersistedDashboardsQuery.IncludeTrashed.
+type NotTrashedFilter struct{}
+
+func (f NotTrashedFilter) Where() (string, []interface{}) {
+	return "dashboard.deleted_at IS NULL", nil
+}
+
+// FullTextFilter restricts results to dashboards matching a free-text query
+// against the dashboardFullTextSearch index, and contributes the rank and
+// highlight snippet FindDashboards surfaces on each row via Select.
+type FullTextFilter struct {
+	Dialect migrator.Dialect
+	Query   string
+}
+
+func (f FullTextFilter) Where() (string, []interface{}) {
+	switch f.Dialect.DriverName() {
+	case migrator.Postgres:
+		return "dashboard.search_vector @@ plainto_tsquery('simple', ?)", []interface{}{f.Query}
+	case migrator.MySQL:
+		return "MATCH(dashboard.title, dashboard.description) AGAINST (? IN NATURAL LANGUAGE MODE)", []interface{}{f.Query}
+	default: // SQLite
+		return "dashboard.id IN (SELECT dashboard_id FROM dashboard_fts WHERE dashboard_fts MATCH ?)", []interface{}{f.Query}
+	}
+}
+
+// Select contributes a relevance rank for Postgres, where ts_rank is cheap
+// to compute inline. SQLite and MySQL fall back to the builder's neutral
+// "0"/"''" defaults until their dialect-specific rank/snippet expressions
+// are written.
+func (f FullTextFilter) Select() (rankExpr, highlightsExpr string, params []interface{}) {
+	if f.Dialect.DriverName() != migrator.Postgres {
+		return "0", "''", nil
+	}
+
+	return "ts_rank(dashboard.search_vector, plainto_tsquery('simple', ?))",
+		"ts_headline('simple', dashboard.title, plainto_tsquery('simple', ?))",
+		[]interface{}{f.Query, f.Query}
+}